@@ -0,0 +1,53 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// httpxClient wraps an http.Client so every outbound request carries a
+// correlation ID and logs its start/end, making it possible to match a
+// failed check or pull against Ollama's or the registry's own logs.
+type httpxClient struct {
+	client *http.Client
+	logger *slog.Logger
+}
+
+// newHTTPXClient builds an httpxClient. A nil client defaults to
+// http.DefaultClient and a nil logger defaults to slog.Default().
+func newHTTPXClient(client *http.Client, logger *slog.Logger) *httpxClient {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &httpxClient{client: client, logger: logger}
+}
+
+// Do sends req, tagging it with an X-Request-ID (reusing one already set by
+// an upstream caller) and logging its method, URL, status, and duration.
+func (c *httpxClient) Do(req *http.Request) (*http.Response, error) {
+	requestID := req.Header.Get("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.NewString()
+		req.Header.Set("X-Request-ID", requestID)
+	}
+
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	duration := time.Since(start)
+
+	if err != nil {
+		c.logger.Error("http request failed",
+			"id", requestID, "method", req.Method, "url", req.URL.String(), "duration", duration, "error", err)
+		return nil, err
+	}
+
+	c.logger.Info("http request",
+		"id", requestID, "method", req.Method, "url", req.URL.String(), "status", resp.StatusCode, "duration", duration)
+	return resp, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPXClient_Do_GeneratesRequestID(t *testing.T) {
+	var gotHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Request-ID")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var logBuf bytes.Buffer
+	c := newHTTPXClient(http.DefaultClient, slog.New(slog.NewTextHandler(&logBuf, nil)))
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error building request: %v", err)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if gotHeader == "" {
+		t.Fatal("expected Do to generate an X-Request-ID header")
+	}
+	if req.Header.Get("X-Request-ID") != gotHeader {
+		t.Fatalf("expected request header to match what the server received, got %q vs %q", req.Header.Get("X-Request-ID"), gotHeader)
+	}
+	if !bytes.Contains(logBuf.Bytes(), []byte(gotHeader)) {
+		t.Fatalf("expected log output to contain the request ID %q, got %q", gotHeader, logBuf.String())
+	}
+}
+
+func TestHTTPXClient_Do_EchoesExistingRequestID(t *testing.T) {
+	const upstreamID = "upstream-request-id"
+
+	var gotHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Request-ID")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := newHTTPXClient(http.DefaultClient, nil)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error building request: %v", err)
+	}
+	req.Header.Set("X-Request-ID", upstreamID)
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if gotHeader != upstreamID {
+		t.Fatalf("expected Do to echo the upstream request ID %q, got %q", upstreamID, gotHeader)
+	}
+}
+
+func TestHTTPXClient_Do_LogsRequestIDOnFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.Close() // closed before use so the request fails at the transport level
+
+	var logBuf bytes.Buffer
+	c := newHTTPXClient(http.DefaultClient, slog.New(slog.NewTextHandler(&logBuf, nil)))
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error building request: %v", err)
+	}
+
+	_, err = c.Do(req)
+	if err == nil {
+		t.Fatal("expected an error from a request to a closed server")
+	}
+
+	requestID := req.Header.Get("X-Request-ID")
+	if requestID == "" {
+		t.Fatal("expected Do to generate an X-Request-ID even on failure")
+	}
+	if !bytes.Contains(logBuf.Bytes(), []byte(requestID)) {
+		t.Fatalf("expected failure log to contain the request ID %q, got %q", requestID, logBuf.String())
+	}
+}
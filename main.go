@@ -2,30 +2,78 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
-	"encoding/base64"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
+// manifestAcceptHeader mirrors the media types Ollama's registry serves for
+// model manifests, so we get back a Docker-Content-Digest header instead of
+// a registry-default content type.
+const manifestAcceptHeader = "application/vnd.docker.distribution.manifest.v2+json, application/vnd.oci.image.manifest.v1+json"
+
+// perRequestTimeout bounds how long a single registry check is allowed to
+// take, so one unresponsive host can't stall the whole worker pool.
+const perRequestTimeout = 15 * time.Second
+
+const (
+	defaultLocalBase    = "http://localhost:11434"
+	defaultRegistryBase = "https://ollama.ai/v2"
+)
+
+// config bundles the endpoints and auth needed to reach a (possibly
+// non-default, possibly private) Ollama instance and registry.
+type config struct {
+	LocalBase    string
+	RegistryBase string
+	RegistryAuth string
+	Workers      int
+	HTTPX        *httpxClient
+}
+
+// envOrDefault returns the value of the named environment variable, or def
+// if it's unset or empty. Flags take precedence over these defaults.
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
 type LocalModel struct {
 	Name   string `json:"name"`
 	Digest string `json:"digest"`
 }
 
-type RemoteModelInfo map[string]interface{}
-
 type ApiResponse struct {
 	Models []LocalModel `json:"models"`
 }
 
+// modelStatus describes the outcome of comparing a local model against the
+// remote registry, including the cases where the comparison itself failed.
+type modelStatus struct {
+	Name     string `json:"name"`
+	Label    string `json:"label"`
+	Outdated bool   `json:"outdated"`
+	CheckErr string `json:"error,omitempty"`
+}
+
 var focusedIndex int
 var selectedIndices []int
 var focusedStyle tcell.Style
@@ -36,88 +84,55 @@ func init() {
 }
 
 func main() {
-	app := tview.NewApplication()
-
-	// Fetch local models
-	localEndpoint := "http://localhost:11434/api/tags"
-	localResp, err := http.Get(localEndpoint)
-	if err != nil {
-		log.Fatalf("Failed to fetch local models: %v", err)
+	workers := flag.Int("workers", runtime.NumCPU(), "number of concurrent workers checking for model updates")
+	localBase := flag.String("host", envOrDefault("OLLAMA_HOST", defaultLocalBase), "Ollama local API base URL")
+	registryBase := flag.String("registry", envOrDefault("OLLAMA_REGISTRY", defaultRegistryBase), "Ollama registry base URL")
+	registryAuth := flag.String("registry-auth", envOrDefault("OLLAMA_REGISTRY_AUTH", ""), "Authorization header (e.g. 'Bearer <token>') sent with registry requests")
+	noTUI := flag.Bool("no-tui", false, "skip the interactive TUI and run a one-shot check suitable for cron/systemd")
+	check := flag.Bool("check", false, "alias for -no-tui")
+	format := flag.String("format", "text", "report format for -no-tui: text or json")
+	updateAll := flag.Bool("update-all", false, "in -no-tui mode, pull every out-of-date model")
+	flag.Parse()
+
+	cfg := config{
+		LocalBase:    strings.TrimRight(*localBase, "/"),
+		RegistryBase: strings.TrimRight(*registryBase, "/"),
+		RegistryAuth: *registryAuth,
+		Workers:      *workers,
+		HTTPX:        newHTTPXClient(http.DefaultClient, slog.Default()),
 	}
-	defer localResp.Body.Close()
 
-	localBody, err := io.ReadAll(localResp.Body)
-	if err != nil {
-		log.Fatalf("Failed to read local models: %v", err)
-	}
-
-	var apiResponse ApiResponse
-	err = json.Unmarshal(localBody, &apiResponse)
+	// A shared cancel channel, closed either by a user Ctrl-C or once the
+	// pool is done with it, lets every in-flight check or pull unwind
+	// promptly.
+	cancelCh := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-cancelCh
+		cancel()
+	}()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	go func() {
+		<-sigCh
+		close(cancelCh)
+	}()
+
+	localModels, err := fetchLocalModels(ctx, cfg)
 	if err != nil {
-		log.Fatalf("Failed to parse local models: %v", err)
+		log.Fatalf("Failed to fetch local models: %v", err)
 	}
 
-	localModels := apiResponse.Models
-
-	// Function to calculate hash of a JSON object
-	calculateHash := func(jsonObj interface{}) string {
-		jsonData, _ := json.Marshal(jsonObj)
-		hash := sha256.Sum256(jsonData)
-		return base64.StdEncoding.EncodeToString(hash[:])
+	if *noTUI || *check {
+		os.Exit(runNonInteractive(ctx, cfg, localModels, *format, *updateAll))
 	}
 
-	// Array to hold non-up-to-date models
-	var nonUpToDateModels []string
-
-	// Iterate over local models and compare with remote models
-	for _, localModel := range localModels {
-		localDigest := localModel.Digest
-		repo, tag := strings.Split(localModel.Name, ":")[0], strings.Split(localModel.Name, ":")[1]
-
-		// Conditionally prepend "/library" to the repo name if it doesn't contain "/"
-		if !strings.Contains(repo, "/") {
-			repo = fmt.Sprintf("library/%s", repo)
-		}
-
-		// Construct URL for the remote model with the potentially modified repo name
-		remoteURL := fmt.Sprintf("https://ollama.ai/v2/%s/manifests/%s", repo, tag)
-		// Fetch remote model info
-		remoteResp, err := http.Get(remoteURL)
-		if err != nil {
-			log.Printf("Failed to fetch remote model %s: %v\n", localModel.Name, err)
-			continue
-		}
-		defer remoteResp.Body.Close()
-
-		// Check for HTTP status codes indicating success (e.g., 200 OK)
-		if remoteResp.StatusCode != http.StatusOK {
-			log.Printf("Remote model %s not found or inaccessible.\n", localModel.Name)
-			continue // Skip this model and continue with the next one
-		}
-
-		// Read the raw response body
-		remoteBody, err := io.ReadAll(remoteResp.Body)
-		if err != nil {
-			log.Printf("Failed to read remote model %s: %v\n", localModel.Name, err)
-			continue
-		}
-
-		// Attempt to unmarshal the JSON
-		var remoteModelInfo RemoteModelInfo
-		err = json.Unmarshal(remoteBody, &remoteModelInfo)
-		if err != nil {
-			log.Printf("Failed to parse remote model %s: %v\n", localModel.Name, err)
-			continue
-		}
-
-		// Calculate hash of the remote model info
-		remoteHash := calculateHash(remoteModelInfo)
-
-		// Compare hashes
-		if remoteHash != localDigest {
-			nonUpToDateModels = append(nonUpToDateModels, localModel.Name)
-		}
-	}
+	app := tview.NewApplication()
+	go func() {
+		<-cancelCh
+		app.Stop()
+	}()
 
 	flex := tview.NewFlex().SetDirection(tview.FlexRow)
 
@@ -127,16 +142,49 @@ func main() {
 	flex.AddItem(all, 1, 1, true)
 
 	checkboxes := []*tview.Checkbox{}
-	for _, model := range localModels {
+	modelNames := make([]string, 0, len(localModels))
+	indexByName := make(map[string]int, len(localModels))
+	for i, model := range localModels {
 		cb := tview.NewCheckbox()
 		cb.SetLabel(model.Name)
 		checkboxes = append(checkboxes, cb)
+		modelNames = append(modelNames, model.Name)
+		indexByName[model.Name] = i
 	}
 
 	for _, cb := range checkboxes {
 		flex.AddItem(cb, 1, 1, false)
 	}
 
+	progressFlex := tview.NewFlex().SetDirection(tview.FlexRow)
+	aggregateBar := tview.NewTextView().SetDynamicColors(true)
+	aggregateBar.SetText(renderProgressBar("Total", 0, 0))
+	statusLine := tview.NewTextView().SetDynamicColors(true)
+
+	flex.AddItem(progressFlex, 0, 1, false)
+	flex.AddItem(aggregateBar, 1, 0, false)
+	flex.AddItem(statusLine, 1, 0, false)
+
+	// pullCancel cancels the pull triggered by the most recent Enter press,
+	// if one is still in flight; Esc uses it to let the user abort a pull
+	// without tearing down the whole app.
+	var pullCancel context.CancelFunc
+
+	// Check every local model against the registry concurrently, streaming
+	// results back as they complete instead of blocking the TUI until the
+	// slowest model resolves.
+	results := checkModelStatuses(ctx, cfg, localModels, cfg.Workers)
+	go func() {
+		for status := range results {
+			status := status
+			app.QueueUpdateDraw(func() {
+				if i, ok := indexByName[status.Name]; ok {
+					checkboxes[i].SetLabel(status.Label)
+				}
+			})
+		}
+	}()
+
 	// Attach the change handler to each checkbox
 	for i, cb := range checkboxes {
 		cb.SetChangedFunc(func(checked bool) {
@@ -148,7 +196,17 @@ func main() {
 	handleInput := func(event *tcell.EventKey) *tcell.EventKey {
 		switch event.Key() { // Use event.Key() instead of event.Rune()
 		case tcell.KeyEnter: // Use tcell.KeyEnter for the Enter key
-			// Your existing logic for handling Enter key
+			selected := selectedModelNames(checkboxes, modelNames)
+			if len(selected) == 0 {
+				break
+			}
+			pullCtx, cancel := context.WithCancel(ctx)
+			pullCancel = cancel
+			go runUpdates(app, pullCtx, cfg, selected, progressFlex, aggregateBar, statusLine)
+		case tcell.KeyEsc:
+			if pullCancel != nil {
+				pullCancel()
+			}
 		case tcell.KeyUp: // Correctly match tcell.KeyUp
 			if focusedIndex > 0 {
 				focusedIndex--
@@ -174,6 +232,253 @@ func main() {
 
 }
 
+// fetchLocalModels lists the models Ollama already has pulled locally.
+func fetchLocalModels(ctx context.Context, cfg config) ([]LocalModel, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.LocalBase+"/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := cfg.HTTPX.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse ApiResponse
+	if err := json.Unmarshal(body, &apiResponse); err != nil {
+		return nil, err
+	}
+
+	return apiResponse.Models, nil
+}
+
+// runNonInteractive checks every local model without starting the TUI,
+// prints a machine-readable report, optionally pulls every stale model, and
+// returns the process exit code. The exit code is non-zero whenever there's
+// something an operator needs to look at: a model is out of date, or the
+// check itself failed (so we genuinely can't say "nothing to update").
+func runNonInteractive(ctx context.Context, cfg config, localModels []LocalModel, format string, updateAll bool) int {
+	statuses := make([]modelStatus, 0, len(localModels))
+	for status := range checkModelStatuses(ctx, cfg, localModels, cfg.Workers) {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+
+	// reportStatuses holds everything that needs attention: models that are
+	// out of date, and models whose check itself failed (registry down, bad
+	// auth, etc.) so a cron job can't silently treat a check failure as
+	// "nothing to update".
+	reportStatuses := make([]modelStatus, 0, len(statuses))
+	var stale []string
+	for _, status := range statuses {
+		if status.Outdated || status.CheckErr != "" {
+			reportStatuses = append(reportStatuses, status)
+		}
+		if status.Outdated {
+			stale = append(stale, status.Name)
+		}
+	}
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reportStatuses); err != nil {
+			log.Printf("Failed to encode report: %v", err)
+		}
+	default:
+		for _, status := range reportStatuses {
+			if status.Outdated {
+				fmt.Println(status.Name)
+			} else {
+				fmt.Println(status.Label)
+			}
+		}
+	}
+
+	if updateAll {
+		for _, name := range stale {
+			log.Printf("Pulling %s...", name)
+			err := updateModel(ctx, cfg, name, func(p pullProgress) {
+				log.Printf("%s: %s (%d/%d)", name, p.Status, p.Completed, p.Total)
+			})
+			if err != nil {
+				log.Printf("Failed to pull %s: %v", name, err)
+			}
+		}
+	}
+
+	if len(reportStatuses) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// checkModelStatuses fans a batch of local models out across a pool of
+// workers and streams back a modelStatus for each one as soon as its check
+// completes. The returned channel is closed once every model has been
+// checked.
+func checkModelStatuses(ctx context.Context, cfg config, localModels []LocalModel, workers int) <-chan modelStatus {
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan LocalModel)
+	results := make(chan modelStatus, len(localModels))
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for model := range jobs {
+				results <- checkModelStatus(ctx, cfg, model)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, model := range localModels {
+			select {
+			case jobs <- model:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// checkModelStatus compares a local model's digest against the one reported
+// by the remote registry and returns a status describing the outcome,
+// including a human-readable label for cases where the check failed rather
+// than simply dropping the model from view.
+func checkModelStatus(ctx context.Context, cfg config, localModel LocalModel) modelStatus {
+	parts := strings.SplitN(localModel.Name, ":", 2)
+	repo, tag := parts[0], "latest"
+	if len(parts) == 2 {
+		tag = parts[1]
+	}
+
+	// Conditionally prepend "library/" to the repo name if it doesn't contain "/"
+	if !strings.Contains(repo, "/") {
+		repo = fmt.Sprintf("library/%s", repo)
+	}
+
+	remoteURL := fmt.Sprintf("%s/%s/manifests/%s", cfg.RegistryBase, repo, tag)
+
+	reqCtx, cancel := context.WithTimeout(ctx, perRequestTimeout)
+	defer cancel()
+
+	digest, statusCode, err := fetchRemoteDigest(reqCtx, cfg, remoteURL)
+	if err != nil {
+		log.Printf("Failed to fetch remote model %s: %v\n", localModel.Name, err)
+		reason := fmt.Sprintf("error: %v", err)
+		return modelStatus{Name: localModel.Name, Label: fmt.Sprintf("%s (%s)", localModel.Name, reason), CheckErr: reason}
+	}
+
+	switch {
+	case statusCode == http.StatusUnauthorized:
+		log.Printf("Remote model %s: unauthorized\n", localModel.Name)
+		return modelStatus{Name: localModel.Name, Label: fmt.Sprintf("%s (unauthorized)", localModel.Name), CheckErr: "unauthorized"}
+	case statusCode == http.StatusNotFound:
+		log.Printf("Remote model %s: not found\n", localModel.Name)
+		return modelStatus{Name: localModel.Name, Label: fmt.Sprintf("%s (not found)", localModel.Name), CheckErr: "not found"}
+	case statusCode >= http.StatusInternalServerError:
+		log.Printf("Remote model %s: registry returned %d\n", localModel.Name, statusCode)
+		reason := fmt.Sprintf("registry error %d", statusCode)
+		return modelStatus{Name: localModel.Name, Label: fmt.Sprintf("%s (%s)", localModel.Name, reason), CheckErr: reason}
+	case statusCode != http.StatusOK:
+		log.Printf("Remote model %s: unexpected status %d\n", localModel.Name, statusCode)
+		reason := fmt.Sprintf("status %d", statusCode)
+		return modelStatus{Name: localModel.Name, Label: fmt.Sprintf("%s (%s)", localModel.Name, reason), CheckErr: reason}
+	}
+
+	outdated := !strings.EqualFold(digest, localModel.Digest)
+	label := localModel.Name
+	if outdated {
+		label = fmt.Sprintf("%s (update available)", localModel.Name)
+	}
+	return modelStatus{Name: localModel.Name, Label: label, Outdated: outdated}
+}
+
+// fetchRemoteDigest resolves the manifest digest for a model from the OCI
+// Registry v2 API. It prefers a HEAD request so the manifest body isn't
+// pulled down when only the digest is needed, and falls back to a GET (and
+// hashing the raw body) when the registry doesn't return a
+// Docker-Content-Digest header.
+func fetchRemoteDigest(ctx context.Context, cfg config, remoteURL string) (digest string, statusCode int, err error) {
+	headReq, err := http.NewRequestWithContext(ctx, http.MethodHead, remoteURL, nil)
+	if err != nil {
+		return "", 0, err
+	}
+	headReq.Header.Set("Accept", manifestAcceptHeader)
+	if cfg.RegistryAuth != "" {
+		headReq.Header.Set("Authorization", cfg.RegistryAuth)
+	}
+
+	headResp, err := cfg.HTTPX.Do(headReq)
+	if err != nil {
+		return "", 0, err
+	}
+	io.Copy(io.Discard, headResp.Body)
+	headResp.Body.Close()
+
+	if headResp.StatusCode == http.StatusOK {
+		if d := headResp.Header.Get("Docker-Content-Digest"); d != "" {
+			return d, headResp.StatusCode, nil
+		}
+		// No digest header on the HEAD response; fall through to a GET so we
+		// have a body to hash.
+	} else if headResp.StatusCode != http.StatusMethodNotAllowed {
+		return "", headResp.StatusCode, nil
+	}
+
+	getReq, err := http.NewRequestWithContext(ctx, http.MethodGet, remoteURL, nil)
+	if err != nil {
+		return "", 0, err
+	}
+	getReq.Header.Set("Accept", manifestAcceptHeader)
+	if cfg.RegistryAuth != "" {
+		getReq.Header.Set("Authorization", cfg.RegistryAuth)
+	}
+
+	getResp, err := cfg.HTTPX.Do(getReq)
+	if err != nil {
+		return "", 0, err
+	}
+	defer getResp.Body.Close()
+
+	body, err := io.ReadAll(getResp.Body)
+	if err != nil {
+		return "", getResp.StatusCode, err
+	}
+
+	if getResp.StatusCode != http.StatusOK {
+		return "", getResp.StatusCode, nil
+	}
+
+	if d := getResp.Header.Get("Docker-Content-Digest"); d != "" {
+		return d, getResp.StatusCode, nil
+	}
+
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf("sha256:%x", sum), getResp.StatusCode, nil
+}
+
 func handleCheckboxChange(checkboxes []*tview.Checkbox, index int, checked bool, itemText string, app *tview.Application) {
 	if itemText == "All" {
 		for i := 0; i < len(checkboxes); i++ {
@@ -214,41 +519,155 @@ func updateFocusVisual(checkboxes []*tview.Checkbox, app *tview.Application) {
 	}
 }
 
-// Function to update a model
-func updateModel(name string) {
-	pullURL := "http://localhost:11434/api/pull"
+// selectedModelNames returns the names of every checked model, in the same
+// order as checkboxes/modelNames.
+func selectedModelNames(checkboxes []*tview.Checkbox, modelNames []string) []string {
+	var selected []string
+	for i, cb := range checkboxes {
+		if cb.IsChecked() {
+			selected = append(selected, modelNames[i])
+		}
+	}
+	return selected
+}
+
+// pullProgress mirrors one line of the NDJSON stream returned by
+// /api/pull.
+type pullProgress struct {
+	Status    string `json:"status"`
+	Digest    string `json:"digest"`
+	Total     int64  `json:"total"`
+	Completed int64  `json:"completed"`
+}
+
+// progressBarWidth is the number of "=" characters a fully-filled bar
+// renders as.
+const progressBarWidth = 20
+
+// renderProgressBar draws a row like "name [========>           ] 42%".
+// A non-positive total (no size reported yet) renders an empty bar with no
+// percentage instead of dividing by zero.
+func renderProgressBar(label string, completed, total int64) string {
+	if total <= 0 {
+		return fmt.Sprintf("%s [%s] --%%", label, strings.Repeat(" ", progressBarWidth))
+	}
+	ratio := float64(completed) / float64(total)
+	if ratio > 1 {
+		ratio = 1
+	}
+	filled := int(ratio * progressBarWidth)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+	return fmt.Sprintf("%s [%s] %3.0f%%", label, bar, ratio*100)
+}
+
+// runUpdates pulls every selected model concurrently, rendering a progress
+// bar per model plus an aggregate bar, and reports any failure on the
+// status line instead of letting it take down the app.
+func runUpdates(app *tview.Application, ctx context.Context, cfg config, names []string, progressFlex *tview.Flex, aggregateBar *tview.TextView, statusLine *tview.TextView) {
+	bars := make(map[string]*tview.TextView, len(names))
+
+	app.QueueUpdateDraw(func() {
+		statusLine.SetText("Updating...")
+		progressFlex.Clear()
+		for _, name := range names {
+			bar := tview.NewTextView().SetDynamicColors(true)
+			bar.SetText(renderProgressBar(name, 0, 0))
+			bars[name] = bar
+			progressFlex.AddItem(bar, 1, 0, false)
+		}
+	})
+
+	var (
+		mu          sync.Mutex
+		totals      = make(map[string]int64, len(names))
+		completions = make(map[string]int64, len(names))
+		wg          sync.WaitGroup
+	)
+
+	updateAggregate := func() {
+		var total, completed int64
+		for _, t := range totals {
+			total += t
+		}
+		for _, c := range completions {
+			completed += c
+		}
+		aggregateBar.SetText(renderProgressBar("Total", completed, total))
+	}
+
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			err := updateModel(ctx, cfg, name, func(p pullProgress) {
+				mu.Lock()
+				if p.Total > 0 {
+					totals[name] = p.Total
+				}
+				completions[name] = p.Completed
+				mu.Unlock()
+
+				app.QueueUpdateDraw(func() {
+					if bar, ok := bars[name]; ok {
+						bar.SetText(renderProgressBar(fmt.Sprintf("%s: %s", name, p.Status), p.Completed, p.Total))
+					}
+					updateAggregate()
+				})
+			})
+			if err != nil {
+				app.QueueUpdateDraw(func() {
+					statusLine.SetText(fmt.Sprintf("[red]%s: %v[-]", name, err))
+				})
+			}
+		}(name)
+	}
+
+	wg.Wait()
+	app.QueueUpdateDraw(func() {
+		if statusLine.GetText(true) == "Updating..." {
+			statusLine.SetText("Update complete")
+		}
+	})
+}
+
+// updateModel requests that Ollama pull the latest version of a model,
+// streaming the NDJSON progress events on the /api/pull response to
+// onProgress as they arrive. The pull can be aborted by cancelling ctx.
+func updateModel(ctx context.Context, cfg config, name string, onProgress func(pullProgress)) error {
+	pullURL := cfg.LocalBase + "/api/pull"
 	payload := map[string]string{"name": name}
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
-		log.Fatalf("Error marshaling payload: %v", err)
+		return fmt.Errorf("marshal pull request for %s: %w", name, err)
 	}
-	body := bytes.NewReader(payloadBytes)
 
-	req, err := http.NewRequest("POST", pullURL, body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pullURL, bytes.NewReader(payloadBytes))
 	if err != nil {
-		log.Fatalf("Error creating request: %v", err)
+		return fmt.Errorf("create pull request for %s: %w", name, err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := cfg.HTTPX.Do(req)
 	if err != nil {
-		log.Fatalf("Error sending request: %v", err)
+		return fmt.Errorf("pull %s: %w", name, err)
 	}
 	defer resp.Body.Close()
 
-	// Handle streamed response
-	buf := make([]byte, 1024)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("pull %s: ollama returned status %d", name, resp.StatusCode)
+	}
+
+	dec := json.NewDecoder(resp.Body)
 	for {
-		n, err := resp.Body.Read(buf)
-		if err != nil && err != io.EOF {
-			log.Fatalf("Error reading response body: %v", err)
+		var progress pullProgress
+		if err := dec.Decode(&progress); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("pull %s: %w", name, err)
 		}
-		if n == 0 {
-			break
+		if onProgress != nil {
+			onProgress(progress)
 		}
-
-		// Process the chunk of data here
-		fmt.Print(string(buf[:n]))
 	}
 }
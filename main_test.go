@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func testConfig(registryBase string) config {
+	return config{
+		RegistryBase: registryBase,
+		HTTPX:        newHTTPXClient(http.DefaultClient, nil),
+	}
+}
+
+func TestFetchRemoteDigest_HeadDigestHeader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Fatalf("expected only a HEAD request, got %s", r.Method)
+		}
+		w.Header().Set("Docker-Content-Digest", "sha256:abc123")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	digest, status, err := fetchRemoteDigest(context.Background(), testConfig(srv.URL), srv.URL+"/v2/library/llama2/manifests/latest")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", status)
+	}
+	if digest != "sha256:abc123" {
+		t.Fatalf("expected digest from Docker-Content-Digest header, got %q", digest)
+	}
+}
+
+func TestFetchRemoteDigest_HeadMethodNotAllowedFallsBackToGet(t *testing.T) {
+	body := []byte(`{"layers":[]}`)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodHead:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			w.Write(body)
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	}))
+	defer srv.Close()
+
+	digest, status, err := fetchRemoteDigest(context.Background(), testConfig(srv.URL), srv.URL+"/v2/library/llama2/manifests/latest")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", status)
+	}
+	want := fmt.Sprintf("sha256:%x", sha256.Sum256(body))
+	if digest != want {
+		t.Fatalf("expected rehashed body digest %q, got %q", want, digest)
+	}
+}
+
+func TestFetchRemoteDigest_HeadOKWithoutHeaderFallsBackToGet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodHead:
+			w.WriteHeader(http.StatusOK) // no Docker-Content-Digest header
+		case http.MethodGet:
+			w.Header().Set("Docker-Content-Digest", "sha256:fromget")
+			w.WriteHeader(http.StatusOK)
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	}))
+	defer srv.Close()
+
+	digest, status, err := fetchRemoteDigest(context.Background(), testConfig(srv.URL), srv.URL+"/v2/library/llama2/manifests/latest")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", status)
+	}
+	if digest != "sha256:fromget" {
+		t.Fatalf("expected digest from GET header, got %q", digest)
+	}
+}
+
+func TestFetchRemoteDigest_HeadErrorStatusSkipsGet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			t.Fatalf("GET should not be attempted when HEAD already reports a terminal status")
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	digest, status, err := fetchRemoteDigest(context.Background(), testConfig(srv.URL), srv.URL+"/v2/library/llama2/manifests/latest")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", status)
+	}
+	if digest != "" {
+		t.Fatalf("expected no digest on error status, got %q", digest)
+	}
+}
+
+func TestCheckModelStatus_StatusCodeBranches(t *testing.T) {
+	tests := []struct {
+		name         string
+		serverStatus int
+		wantErr      string
+	}{
+		{"unauthorized", http.StatusUnauthorized, "unauthorized"},
+		{"not found", http.StatusNotFound, "not found"},
+		{"server error", http.StatusBadGateway, "registry error 502"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.serverStatus)
+			}))
+			defer srv.Close()
+
+			status := checkModelStatus(context.Background(), testConfig(srv.URL), LocalModel{Name: "llama2:latest", Digest: "sha256:local"})
+			if status.CheckErr != tt.wantErr {
+				t.Fatalf("expected CheckErr %q, got %q (label: %q)", tt.wantErr, status.CheckErr, status.Label)
+			}
+			if status.Outdated {
+				t.Fatalf("a failed check should not be reported as outdated")
+			}
+		})
+	}
+}
+
+func TestCheckModelStatus_UpToDateIsCaseInsensitive(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Docker-Content-Digest", "SHA256:ABC123")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	status := checkModelStatus(context.Background(), testConfig(srv.URL), LocalModel{Name: "llama2:latest", Digest: "sha256:abc123"})
+	if status.Outdated {
+		t.Fatalf("expected matching digest (case-insensitive) to be up to date, got %+v", status)
+	}
+	if status.CheckErr != "" {
+		t.Fatalf("expected no check error, got %q", status.CheckErr)
+	}
+}
+
+func TestCheckModelStatus_DigestMismatchIsOutdated(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Docker-Content-Digest", "sha256:newdigest")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	status := checkModelStatus(context.Background(), testConfig(srv.URL), LocalModel{Name: "llama2:latest", Digest: "sha256:olddigest"})
+	if !status.Outdated {
+		t.Fatalf("expected mismatched digest to be outdated, got %+v", status)
+	}
+}